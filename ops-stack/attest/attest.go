@@ -0,0 +1,136 @@
+// Package attest implements DSSE (Dead Simple Signing Envelope) signing and
+// verification over canonical-JSON payloads, in the style of in-toto
+// attestations: Sign wraps an arbitrary value in a signed envelope, and
+// Verify checks the signatures and returns the decoded statement.
+//
+// Signatures are computed over the envelope's DSSE pre-authentication
+// encoding (PAE), not over the raw canonical JSON. This keeps signing and
+// canonicalization independent concerns: a verifier that disagrees with the
+// producer about whitespace or key order still sees the exact bytes that
+// were signed, because the payload bytes are carried unmodified inside the
+// envelope and PAE binds the payload type alongside them.
+package attest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/EvezArt/openclaw/ops-stack/canonicaljson"
+)
+
+// PayloadTypeInToto is the DSSE payload type used for in-toto attestations.
+const PayloadTypeInToto = "application/vnd.in-toto+json"
+
+// Signer produces a signature over an arbitrary byte string and reports the
+// identifier of the key it signed with, so Verify can match signatures to
+// public keys.
+type Signer interface {
+	KeyID() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// PublicKey verifies a signature produced by the Signer with the matching
+// KeyID. Verify should return a non-nil error whenever the signature does
+// not check out.
+type PublicKey interface {
+	KeyID() string
+	Verify(data, sig []byte) error
+}
+
+// Signature is one signer's signature over an envelope's PAE.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// Envelope is a DSSE envelope: a typed payload and one or more signatures
+// over its pre-authentication encoding.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     []byte      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Statement is the decoded contents of a verified envelope's payload.
+type Statement struct {
+	raw json.RawMessage
+}
+
+// Unmarshal decodes the statement's payload into v.
+func (s Statement) Unmarshal(v interface{}) error {
+	return json.Unmarshal(s.raw, v)
+}
+
+// PAE computes the DSSE v1 pre-authentication encoding of payloadType and
+// payload: "DSSEv1" SP len(type) SP type SP len(payload) SP payload.
+//
+// Signing over PAE rather than over payload alone binds the payload type
+// into the signature, so a signature for one payload type can't be replayed
+// as a signature for another.
+func PAE(payloadType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("DSSEv1 ")
+	buf.WriteString(strconv.Itoa(len(payloadType)))
+	buf.WriteByte(' ')
+	buf.WriteString(payloadType)
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.Itoa(len(payload)))
+	buf.WriteByte(' ')
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// Sign canonicalizes payload as an in-toto JSON payload and signs its DSSE
+// pre-authentication encoding with signer.
+func Sign(payload interface{}, signer Signer) (Envelope, error) {
+	body, err := canonicaljson.Transform(payload)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("attest: canonicalize payload: %w", err)
+	}
+	sig, err := signer.Sign(PAE(PayloadTypeInToto, body))
+	if err != nil {
+		return Envelope{}, fmt.Errorf("attest: sign: %w", err)
+	}
+	return Envelope{
+		PayloadType: PayloadTypeInToto,
+		Payload:     body,
+		Signatures: []Signature{{
+			KeyID: signer.KeyID(),
+			Sig:   sig,
+		}},
+	}, nil
+}
+
+// Verify checks env's signatures against keys and, once any one of them
+// verifies, returns the decoded statement. A signature carrying a KeyID is
+// checked only against the matching key; a signature with no KeyID is
+// checked against every key in turn.
+func Verify(env Envelope, keys []PublicKey) (Statement, error) {
+	if len(env.Signatures) == 0 {
+		return Statement{}, errors.New("attest: envelope has no signatures")
+	}
+	pae := PAE(env.PayloadType, env.Payload)
+
+	byID := make(map[string]PublicKey, len(keys))
+	for _, k := range keys {
+		byID[k.KeyID()] = k
+	}
+
+	for _, sig := range env.Signatures {
+		if sig.KeyID != "" {
+			if key, ok := byID[sig.KeyID]; ok && key.Verify(pae, sig.Sig) == nil {
+				return Statement{raw: env.Payload}, nil
+			}
+			continue
+		}
+		for _, key := range keys {
+			if key.Verify(pae, sig.Sig) == nil {
+				return Statement{raw: env.Payload}, nil
+			}
+		}
+	}
+	return Statement{}, errors.New("attest: no signature verified against the provided keys")
+}
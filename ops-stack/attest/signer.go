@@ -0,0 +1,105 @@
+package attest
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ED25519Signer signs with an ed25519 private key.
+type ED25519Signer struct {
+	KeyIDValue string
+	PrivateKey ed25519.PrivateKey
+}
+
+func (s ED25519Signer) KeyID() string { return s.KeyIDValue }
+
+func (s ED25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(s.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("attest: invalid ed25519 private key")
+	}
+	return ed25519.Sign(s.PrivateKey, data), nil
+}
+
+// ED25519PublicKey verifies signatures produced by an ED25519Signer.
+type ED25519PublicKey struct {
+	KeyIDValue string
+	PublicKey  ed25519.PublicKey
+}
+
+func (k ED25519PublicKey) KeyID() string { return k.KeyIDValue }
+
+func (k ED25519PublicKey) Verify(data, sig []byte) error {
+	if !ed25519.Verify(k.PublicKey, data, sig) {
+		return errors.New("attest: ed25519 signature verification failed")
+	}
+	return nil
+}
+
+// ECDSAP256Signer signs with an ECDSA P-256 private key. The input is
+// hashed with SHA-256 and signed as an ASN.1 DER signature.
+type ECDSAP256Signer struct {
+	KeyIDValue string
+	PrivateKey *ecdsa.PrivateKey
+}
+
+func (s ECDSAP256Signer) KeyID() string { return s.KeyIDValue }
+
+func (s ECDSAP256Signer) Sign(data []byte) ([]byte, error) {
+	if s.PrivateKey == nil || s.PrivateKey.Curve != elliptic.P256() {
+		return nil, errors.New("attest: ECDSAP256Signer requires a P-256 private key")
+	}
+	digest := sha256.Sum256(data)
+	return ecdsa.SignASN1(rand.Reader, s.PrivateKey, digest[:])
+}
+
+// ECDSAP256PublicKey verifies signatures produced by an ECDSAP256Signer.
+type ECDSAP256PublicKey struct {
+	KeyIDValue string
+	PublicKey  *ecdsa.PublicKey
+}
+
+func (k ECDSAP256PublicKey) KeyID() string { return k.KeyIDValue }
+
+func (k ECDSAP256PublicKey) Verify(data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(k.PublicKey, digest[:], sig) {
+		return errors.New("attest: ECDSA P-256 signature verification failed")
+	}
+	return nil
+}
+
+// KMSClient is implemented by external key-management backends (e.g. a
+// cloud KMS) that sign a digest without exposing private key material to
+// this process.
+type KMSClient interface {
+	// SignDigest signs a SHA-256 digest under the key identified by keyID
+	// and returns the raw signature.
+	SignDigest(keyID string, digest [32]byte) ([]byte, error)
+}
+
+// KMSSigner signs DSSE payloads via a remote KMSClient. The input is hashed
+// locally with SHA-256 so only the digest, never the raw payload, is sent
+// to the backend.
+type KMSSigner struct {
+	KeyIDValue string
+	Client     KMSClient
+}
+
+func (s KMSSigner) KeyID() string { return s.KeyIDValue }
+
+func (s KMSSigner) Sign(data []byte) ([]byte, error) {
+	if s.Client == nil {
+		return nil, errors.New("attest: KMSSigner requires a Client")
+	}
+	digest := sha256.Sum256(data)
+	sig, err := s.Client.SignDigest(s.KeyIDValue, digest)
+	if err != nil {
+		return nil, fmt.Errorf("attest: KMS sign: %w", err)
+	}
+	return sig, nil
+}
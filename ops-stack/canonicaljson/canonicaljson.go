@@ -0,0 +1,101 @@
+// Package canonicaljson produces deterministic, byte-for-byte canonical JSON
+// encodings of arbitrary Go values, suitable for hashing and signing.
+//
+// Two encoding schemes are supported:
+//
+//   - JCS: RFC 8785 JSON Canonicalization Scheme (the default).
+//   - OLPC: the OLPC "Canonical JSON" dialect used by TUF and in-toto.
+//
+// Both schemes guarantee that semantically equal inputs always produce the
+// same byte sequence, which is the property that hashing and signing over
+// JSON depends on. json.Marshal alone does not provide this: it does not
+// sort map keys consistently across schemes, and its number/escaping rules
+// do not match either spec.
+package canonicaljson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// Scheme selects a canonicalization dialect.
+type Scheme int
+
+const (
+	// JCS canonicalizes per RFC 8785 and is the default scheme.
+	JCS Scheme = iota
+	// OLPC canonicalizes per the OLPC Canonical JSON dialect used by TUF
+	// and in-toto.
+	OLPC
+)
+
+func (s Scheme) String() string {
+	switch s {
+	case JCS:
+		return "JCS"
+	case OLPC:
+		return "OLPC"
+	default:
+		return fmt.Sprintf("Scheme(%d)", int(s))
+	}
+}
+
+// Transform canonicalizes v and returns its canonical JSON encoding using
+// the default scheme (JCS). Use TransformScheme to select OLPC instead.
+func Transform(v interface{}) ([]byte, error) {
+	return TransformScheme(v, JCS)
+}
+
+// TransformScheme canonicalizes v under the given scheme.
+//
+// v is first marshaled with encoding/json (so struct tags, MarshalJSON
+// implementations, etc. are honored) and then re-parsed into the decoded
+// form the scheme's encoder walks. Numbers are decoded with json.Number so
+// that no precision is lost before canonicalization, and objects are
+// decoded with decodeStrict rather than encoding/json's own map decoding,
+// which would otherwise silently keep the last value of a duplicate key
+// instead of rejecting it as I-JSON requires.
+func TransformScheme(v interface{}, scheme Scheme) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonicaljson: marshal: %w", err)
+	}
+
+	node, err := decodeStrict(raw)
+	if err != nil {
+		return nil, fmt.Errorf("canonicaljson: decode: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch scheme {
+	case JCS:
+		if err := encodeJCS(&buf, node); err != nil {
+			return nil, err
+		}
+	case OLPC:
+		if err := encodeOLPC(&buf, node); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("canonicaljson: unknown scheme %s", scheme)
+	}
+	return buf.Bytes(), nil
+}
+
+// HashSHA256 canonicalizes v under the default JCS scheme and returns the
+// SHA-256 digest of the canonical encoding.
+func HashSHA256(v interface{}) ([32]byte, error) {
+	return HashSHA256Scheme(v, JCS)
+}
+
+// HashSHA256Scheme canonicalizes v under the given scheme and returns the
+// SHA-256 digest of the canonical encoding.
+func HashSHA256Scheme(v interface{}, scheme Scheme) ([32]byte, error) {
+	canon, err := TransformScheme(v, scheme)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(canon), nil
+}
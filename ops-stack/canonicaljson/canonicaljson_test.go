@@ -0,0 +1,209 @@
+package canonicaljson
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+)
+
+// testData mirrors ops-stack/examples/example.go's TestData fixture.
+type testData struct {
+	TestID    string                 `json:"testId"`
+	Timestamp string                 `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+func goldenFixture() testData {
+	return testData{
+		TestID:    "golden-hash-test-v1",
+		Timestamp: "2024-01-01T00:00:00.000Z",
+		Data: map[string]interface{}{
+			"modules": []string{"market-intelligence", "notifications", "automation", "monetization", "ai-engine"},
+			"config": map[string]interface{}{
+				"environment": "test",
+				"version":     "1.0.0",
+			},
+			"nested": map[string]interface{}{
+				"array": []int{3, 1, 2},
+				"object": map[string]string{
+					"z": "last",
+					"a": "first",
+					"m": "middle",
+				},
+			},
+		},
+	}
+}
+
+const goldenJCS = `{"data":{"config":{"environment":"test","version":"1.0.0"},"modules":["market-intelligence","notifications","automation","monetization","ai-engine"],"nested":{"array":[3,1,2],"object":{"a":"first","m":"middle","z":"last"}}},"testId":"golden-hash-test-v1","timestamp":"2024-01-01T00:00:00.000Z"}`
+
+func TestTransform_GoldenFixture_JCS(t *testing.T) {
+	got, err := Transform(goldenFixture())
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	if string(got) != goldenJCS {
+		t.Fatalf("JCS output mismatch:\n got:  %s\n want: %s", got, goldenJCS)
+	}
+}
+
+func TestTransformScheme_GoldenFixture_OLPC(t *testing.T) {
+	// This fixture has no floats and no non-ASCII, so OLPC and JCS agree:
+	// both sort keys and neither needs anything beyond quote/backslash
+	// escaping.
+	got, err := TransformScheme(goldenFixture(), OLPC)
+	if err != nil {
+		t.Fatalf("TransformScheme(OLPC): %v", err)
+	}
+	if string(got) != goldenJCS {
+		t.Fatalf("OLPC output mismatch:\n got:  %s\n want: %s", got, goldenJCS)
+	}
+}
+
+func TestHashSHA256_MatchesTransform(t *testing.T) {
+	canon, err := Transform(goldenFixture())
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	want := sha256.Sum256(canon)
+
+	got, err := HashSHA256(goldenFixture())
+	if err != nil {
+		t.Fatalf("HashSHA256: %v", err)
+	}
+	if got != want {
+		t.Fatalf("hash mismatch: got %x want %x", got, want)
+	}
+}
+
+// TestTransform_KeyOrderingAndEscaping pins JCS's UTF-16 key ordering (RFC
+// 8785 §3.2.3) and its minimal string escaping (§3.2.2.2) against a fixture
+// mixing ASCII, a combining-free accented letter, and U+FFFF.
+func TestTransform_KeyOrderingAndEscaping(t *testing.T) {
+	m := map[string]interface{}{
+		"é":      1,
+		"a":      2,
+		"￿":      3,
+		"b":      4,
+		"escape": "tab\tnewline\nquote\"back\\slash",
+	}
+	const want = `{"a":2,"b":4,"escape":"tab\tnewline\nquote\"back\\slash","é":1,"￿":3}`
+
+	got, err := Transform(m)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("JCS output mismatch:\n got:  %s\n want: %s", got, want)
+	}
+}
+
+// TestTransformScheme_OLPCEscaping pins OLPC's narrower escaping rule: only
+// quote and backslash are escaped, so raw tabs and newlines pass through.
+func TestTransformScheme_OLPCEscaping(t *testing.T) {
+	m := map[string]interface{}{"escape": "tab\tnewline\nquote\"back\\slash"}
+	const want = "{\"escape\":\"tab\tnewline\nquote\\\"back\\\\slash\"}"
+
+	got, err := TransformScheme(m, OLPC)
+	if err != nil {
+		t.Fatalf("TransformScheme(OLPC): %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("OLPC output mismatch:\n got:  %q\n want: %q", got, want)
+	}
+}
+
+// TestJCSNumberFormatting pins RFC 8785 §3.2.2.3's ECMAScript-derived number
+// formatting against known ECMAScript Number::toString outputs.
+func TestJCSNumberFormatting(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"-0", "0"},
+		{"100", "100"},
+		{"1.5", "1.5"},
+		{"1e21", "1e+21"},
+		{"1e-7", "1e-7"},
+		{"0.000001", "0.000001"}, // 1e-6: still fixed notation, one above the -7 cutover
+		{"9007199254740992", "9007199254740992"},   // 2^53, the largest safe integer
+		{"-9007199254740992", "-9007199254740992"}, // -2^53
+	}
+	for _, c := range cases {
+		got, err := Transform(struct {
+			N json.Number `json:"n"`
+		}{N: json.Number(c.in)})
+		if err != nil {
+			t.Errorf("Transform(%s): unexpected error: %v", c.in, err)
+			continue
+		}
+		want := `{"n":` + c.want + `}`
+		if string(got) != want {
+			t.Errorf("Transform(%s) = %s, want %s", c.in, got, want)
+		}
+	}
+}
+
+// TestJCSNumber_RejectsUnsafeIntegers pins the I-JSON ±2^53 safe-integer
+// bound: integer literals whose magnitude exceeds 2^53 can't be represented
+// exactly as a JavaScript number, so JCS must reject them rather than
+// silently re-encoding whatever a float64 round-trip corrupted them into.
+func TestJCSNumber_RejectsUnsafeIntegers(t *testing.T) {
+	for _, in := range []string{"9007199254740993", "-9007199254740993", "18446744073709551615"} {
+		_, err := Transform(struct {
+			N json.Number `json:"n"`
+		}{N: json.Number(in)})
+		if err == nil {
+			t.Errorf("Transform(%s): expected an out-of-range error, got none", in)
+		}
+	}
+}
+
+// TestTransform_RejectsDuplicateKeys pins I-JSON's "no duplicate keys"
+// constraint. encoding/json's own map decoding silently keeps the last
+// value of a duplicate key, so this only exercises end to end if
+// TransformScheme's decode path rejects duplicates itself.
+func TestTransform_RejectsDuplicateKeys(t *testing.T) {
+	_, err := Transform(json.RawMessage(`{"a":1,"a":2}`))
+	if err == nil {
+		t.Fatal("expected an error for a duplicate object key, got none")
+	}
+}
+
+// TestTransformScheme_OLPCAcceptsBigIntegers pins that OLPC, unlike JCS, has
+// no magnitude bound on integers: it forbids floats, not bignums, so a
+// 27-digit integer literal (far outside int64 range) must still canonicalize
+// rather than being rejected with a "forbids non-integer number" error that
+// misdescribes why it was rejected.
+func TestTransformScheme_OLPCAcceptsBigIntegers(t *testing.T) {
+	const big = "123456789012345678901234567"
+	got, err := TransformScheme(struct {
+		N json.Number `json:"n"`
+	}{N: json.Number(big)}, OLPC)
+	if err != nil {
+		t.Fatalf("TransformScheme(OLPC): unexpected error: %v", err)
+	}
+	want := `{"n":` + big + `}`
+	if string(got) != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestTransformScheme_OLPCRejectsFloats(t *testing.T) {
+	_, err := TransformScheme(struct {
+		N json.Number `json:"n"`
+	}{N: json.Number("1.5")}, OLPC)
+	if err == nil {
+		t.Fatal("expected an error for a non-integer number under OLPC, got none")
+	}
+}
+
+func TestTransform_RejectsNaNAndInf(t *testing.T) {
+	for _, in := range []string{"NaN", "Infinity", "-Infinity"} {
+		_, err := Transform(json.RawMessage(`{"n":` + in + `}`))
+		if err == nil {
+			t.Errorf("Transform with n=%s: expected an error, got none", in)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodeStrict parses raw the same way json.Decoder.Decode(&interface{})
+// does, except it rejects object literals with duplicate keys instead of
+// silently keeping the last value, as I-JSON (and RFC 8785, which is
+// defined over I-JSON) requires.
+func decodeStrict(raw []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	node, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("canonicaljson: unexpected trailing data after top-level value")
+		}
+		return nil, err
+	}
+	return node, nil
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeToken(dec, tok)
+}
+
+func decodeToken(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return decodeObject(dec)
+		case '[':
+			return decodeArray(dec)
+		default:
+			return nil, fmt.Errorf("canonicaljson: unexpected delimiter %q", t)
+		}
+	default:
+		// nil, bool, json.Number, string all come through as-is.
+		return tok, nil
+	}
+}
+
+// decodeObject reads a JSON object whose opening '{' has already been
+// consumed, erroring if any key repeats.
+func decodeObject(dec *json.Decoder) (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return nil, fmt.Errorf("canonicaljson: object key %v is not a string", keyTok)
+		}
+		if _, dup := obj[key]; dup {
+			return nil, fmt.Errorf("canonicaljson: duplicate object key %q", key)
+		}
+		val, err := decodeValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = val
+	}
+	if _, err := dec.Token(); err != nil { // consume closing '}'
+		return nil, err
+	}
+	return obj, nil
+}
+
+// decodeArray reads a JSON array whose opening '[' has already been
+// consumed.
+func decodeArray(dec *json.Decoder) ([]interface{}, error) {
+	var arr []interface{}
+	for dec.More() {
+		val, err := decodeValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, val)
+	}
+	if _, err := dec.Token(); err != nil { // consume closing ']'
+		return nil, err
+	}
+	return arr, nil
+}
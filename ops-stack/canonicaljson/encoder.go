@@ -0,0 +1,419 @@
+package canonicaljson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxDepth is the MaxDepth an Encoder uses when none is set.
+const DefaultMaxDepth = 10000
+
+// Encoder streams RFC 8785 JCS-canonical JSON directly to an io.Writer,
+// without ever holding the full encoded output in memory. The only
+// buffering it does is per object: a map or struct's keys must be sorted
+// before any of them can be written, so one object's keys are gathered
+// (from a pooled scratch slice) immediately before that object is written,
+// and released immediately after. Arrays and scalars are written straight
+// through as they're visited.
+//
+// This trades the convenience of TransformScheme, which marshals the whole
+// value up front, for bounded memory use on large values: peak memory is
+// proportional to the widest single object, not to the size of v.
+type Encoder struct {
+	w io.Writer
+
+	// MaxDepth bounds recursion into nested arrays/objects, guarding
+	// against stack exhaustion on adversarially nested input. Zero means
+	// DefaultMaxDepth.
+	MaxDepth int
+}
+
+// NewEncoder returns an Encoder that writes canonical JCS JSON to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode canonicalizes v and streams it to the Encoder's writer.
+func (e *Encoder) Encode(v interface{}) error {
+	maxDepth := e.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	return e.encodeValue(reflect.ValueOf(v), 0, maxDepth)
+}
+
+func (e *Encoder) encodeValue(rv reflect.Value, depth, maxDepth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("canonicaljson: exceeded MaxDepth %d", maxDepth)
+	}
+	if !rv.IsValid() {
+		_, err := io.WriteString(e.w, "null")
+		return err
+	}
+
+	for rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			_, err := io.WriteString(e.w, "null")
+			return err
+		}
+		rv = rv.Elem()
+	}
+
+	// A type's own json.Marshaler/encoding.TextMarshaler takes priority over
+	// reflecting into its fields, exactly as encoding/json does: time.Time,
+	// uuid.UUID, and similar types have a canonical JSON form that isn't
+	// their (often unexported) struct fields.
+	if handled, err := e.encodeMarshaler(rv); handled {
+		return err
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			_, err := io.WriteString(e.w, "null")
+			return err
+		}
+		return e.encodeValue(rv.Elem(), depth, maxDepth)
+
+	case reflect.Bool:
+		if rv.Bool() {
+			_, err := io.WriteString(e.w, "true")
+			return err
+		}
+		_, err := io.WriteString(e.w, "false")
+		return err
+
+	case reflect.String:
+		if n, ok := rv.Interface().(json.Number); ok {
+			return e.writeNumber(n)
+		}
+		return writeJCSString(e.w, rv.String())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.writeNumber(json.Number(strconv.FormatInt(rv.Int(), 10)))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return e.writeNumber(json.Number(strconv.FormatUint(rv.Uint(), 10)))
+
+	case reflect.Float32, reflect.Float64:
+		return e.writeNumber(json.Number(strconv.FormatFloat(rv.Float(), 'g', -1, 64)))
+
+	case reflect.Slice, reflect.Array:
+		return e.encodeSequence(rv, depth, maxDepth)
+
+	case reflect.Map:
+		return e.encodeMap(rv, depth, maxDepth)
+
+	case reflect.Struct:
+		return e.encodeStruct(rv, depth, maxDepth)
+
+	default:
+		return fmt.Errorf("canonicaljson: unsupported type %s", rv.Type())
+	}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeMarshaler checks whether rv implements json.Marshaler or
+// encoding.TextMarshaler, directly or (if rv is addressable) through its
+// pointer, mirroring encoding/json's own method-set rules. If so, it
+// encodes rv by running MarshalJSON's output back through decodeStrict and
+// encodeJCS (MarshalText's output is just a JCS string), rather than
+// reflecting over rv's fields.
+func (e *Encoder) encodeMarshaler(rv reflect.Value) (handled bool, err error) {
+	if m, ok := asJSONMarshaler(rv); ok {
+		raw, err := m.MarshalJSON()
+		if err != nil {
+			return true, fmt.Errorf("canonicaljson: MarshalJSON: %w", err)
+		}
+		node, err := decodeStrict(raw)
+		if err != nil {
+			return true, fmt.Errorf("canonicaljson: decode MarshalJSON output: %w", err)
+		}
+		return true, encodeJCS(e.w, node)
+	}
+	if m, ok := asTextMarshaler(rv); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return true, fmt.Errorf("canonicaljson: MarshalText: %w", err)
+		}
+		return true, writeJCSString(e.w, string(text))
+	}
+	return false, nil
+}
+
+func asJSONMarshaler(rv reflect.Value) (json.Marshaler, bool) {
+	if rv.Type().Implements(jsonMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, false
+		}
+		return rv.Interface().(json.Marshaler), true
+	}
+	if rv.CanAddr() && reflect.PtrTo(rv.Type()).Implements(jsonMarshalerType) {
+		return rv.Addr().Interface().(json.Marshaler), true
+	}
+	return nil, false
+}
+
+func asTextMarshaler(rv reflect.Value) (encoding.TextMarshaler, bool) {
+	if rv.Type().Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, false
+		}
+		return rv.Interface().(encoding.TextMarshaler), true
+	}
+	if rv.CanAddr() && reflect.PtrTo(rv.Type()).Implements(textMarshalerType) {
+		return rv.Addr().Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
+
+func (e *Encoder) writeNumber(n json.Number) error {
+	s, err := jcsNumber(n)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, s)
+	return err
+}
+
+func (e *Encoder) encodeSequence(rv reflect.Value, depth, maxDepth int) error {
+	if rv.Kind() == reflect.Slice {
+		if rv.IsNil() {
+			_, err := io.WriteString(e.w, "null")
+			return err
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte marshals the same way encoding/json does: base64.
+			return writeJCSString(e.w, base64.StdEncoding.EncodeToString(rv.Bytes()))
+		}
+	}
+
+	if _, err := io.WriteString(e.w, "["); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		if err := e.encodeValue(rv.Index(i), depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+// keyScratchPool holds reusable []string buffers for sorting one object's
+// keys at a time, so encoding a large document doesn't allocate a fresh key
+// slice per nested object.
+var keyScratchPool = sync.Pool{
+	New: func() interface{} {
+		s := make([]string, 0, 16)
+		return &s
+	},
+}
+
+func getKeyScratch() *[]string {
+	return keyScratchPool.Get().(*[]string)
+}
+
+func putKeyScratch(s *[]string) {
+	*s = (*s)[:0]
+	keyScratchPool.Put(s)
+}
+
+func (e *Encoder) encodeMap(rv reflect.Value, depth, maxDepth int) error {
+	if rv.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("canonicaljson: map key type %s not supported (must be string)", rv.Type().Key())
+	}
+	if rv.IsNil() {
+		_, err := io.WriteString(e.w, "null")
+		return err
+	}
+
+	keysPtr := getKeyScratch()
+	defer putKeyScratch(keysPtr)
+	keys := *keysPtr
+
+	mapIter := rv.MapRange()
+	for mapIter.Next() {
+		keys = append(keys, mapIter.Key().String())
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+	*keysPtr = keys
+
+	if _, err := io.WriteString(e.w, "{"); err != nil {
+		return err
+	}
+	keyType := rv.Type().Key()
+	for i, k := range keys {
+		if i > 0 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		if err := writeJCSString(e.w, k); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, ":"); err != nil {
+			return err
+		}
+		val := rv.MapIndex(reflect.ValueOf(k).Convert(keyType))
+		if err := e.encodeValue(val, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.w, "}")
+	return err
+}
+
+func (e *Encoder) encodeStruct(rv reflect.Value, depth, maxDepth int) error {
+	keysPtr := getKeyScratch()
+	defer putKeyScratch(keysPtr)
+	keys := *keysPtr
+
+	fields := make(map[string]reflect.Value, rv.NumField())
+	collectStructFields(rv, fields, &keys)
+	sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+	*keysPtr = keys
+
+	if _, err := io.WriteString(e.w, "{"); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		if i > 0 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		if err := writeJCSString(e.w, k); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, ":"); err != nil {
+			return err
+		}
+		if err := e.encodeValue(fields[k], depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.w, "}")
+	return err
+}
+
+// collectStructFields walks rv's fields into fields/keys, promoting
+// anonymous (embedded) struct fields the way encoding/json does: an
+// anonymous field with no explicit JSON name contributes its own exported
+// fields at the outer level instead of nesting under the embedded type's
+// name. A struct's own fields are collected before any promoted ones, so an
+// outer field shadows a promoted field of the same name; among promoted
+// fields the first one seen is kept. This matches encoding/json's
+// shallowest-wins rule for the common case of no sibling embeds at the same
+// depth.
+func collectStructFields(rv reflect.Value, fields map[string]reflect.Value, keys *[]string) {
+	t := rv.Type()
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+
+		if f.Anonymous && !hasJSONName(f) {
+			fv := rv.Field(i)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				ft = ft.Elem()
+				fv = fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+
+		name, omitempty, skip := jsonFieldTag(f)
+		if skip {
+			continue
+		}
+		fv := rv.Field(i)
+		if omitempty && isEmptyValue(fv) {
+			continue
+		}
+		if _, exists := fields[name]; exists {
+			continue
+		}
+		*keys = append(*keys, name)
+		fields[name] = fv
+	}
+	for _, fv := range embedded {
+		collectStructFields(fv, fields, keys)
+	}
+}
+
+// hasJSONName reports whether f's json tag gives it an explicit name, which
+// per encoding/json disables anonymous-field promotion: the field is
+// encoded as a regular named field instead.
+func hasJSONName(f reflect.StructField) bool {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return false
+	}
+	return strings.Split(tag, ",")[0] != ""
+}
+
+// jsonFieldTag extracts the effective JSON name, omitempty flag, and
+// skip (json:"-") status for a struct field, following encoding/json's
+// tag conventions.
+func jsonFieldTag(f reflect.StructField) (name string, omitempty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = f.Name
+	if parts[0] != "" {
+		name = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
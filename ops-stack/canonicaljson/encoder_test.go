@@ -0,0 +1,174 @@
+package canonicaljson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncoder_MatchesTransform checks that the streaming Encoder agrees
+// byte-for-byte with Transform across maps, slices, structs, and scalars,
+// since the two are expected to implement the same JCS rules via two
+// different code paths (interface{}-tree walk vs. reflection).
+func TestEncoder_MatchesTransform(t *testing.T) {
+	cases := []interface{}{
+		goldenFixture(),
+		map[string]interface{}{"b": 1, "a": []int{1, 2, 3}, "z": nil},
+		[]interface{}{1, "two", true, nil, map[string]interface{}{"k": 1}},
+		struct {
+			Name    string `json:"name"`
+			Skip    string `json:"-"`
+			Omitted string `json:"omitted,omitempty"`
+			Count   int    `json:"count"`
+		}{Name: "x", Skip: "hidden", Count: 3},
+	}
+
+	for i, v := range cases {
+		want, err := Transform(v)
+		if err != nil {
+			t.Fatalf("case %d: Transform: %v", i, err)
+		}
+
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf).Encode(v); err != nil {
+			t.Fatalf("case %d: Encoder.Encode: %v", i, err)
+		}
+		if buf.String() != string(want) {
+			t.Errorf("case %d: Encoder output mismatch:\n got:  %s\n want: %s", i, buf.String(), want)
+		}
+	}
+}
+
+func TestEncoder_StructOmitsTagged(t *testing.T) {
+	v := struct {
+		Name    string `json:"name"`
+		Skip    string `json:"-"`
+		Omitted string `json:"omitted,omitempty"`
+	}{Name: "x", Skip: "hidden"}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	const want = `{"name":"x"}`
+	if buf.String() != want {
+		t.Fatalf("got %s, want %s", buf.String(), want)
+	}
+}
+
+// TestEncoder_HonorsMarshalJSON pins that the Encoder consults a type's own
+// MarshalJSON, the way encoding/json does, rather than reflecting over its
+// (often unexported) struct fields: time.Time's canonical JSON form is its
+// RFC 3339 string, not "{}".
+func TestEncoder_HonorsMarshalJSON(t *testing.T) {
+	v := struct {
+		At time.Time `json:"at"`
+	}{At: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	want, err := Transform(v)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encoder.Encode: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Fatalf("Encoder output mismatch:\n got:  %s\n want: %s", buf.String(), want)
+	}
+	const wantSubstring = `"2024-01-02T03:04:05Z"`
+	if !strings.Contains(buf.String(), wantSubstring) {
+		t.Fatalf("Encoder output %s does not contain RFC3339 timestamp %s", buf.String(), wantSubstring)
+	}
+}
+
+// TestEncoder_PromotesAnonymousFields pins that an anonymous struct field
+// with no explicit json tag name has its own fields promoted to the outer
+// object, as encoding/json does, instead of nesting under the embedded
+// type's name.
+func TestEncoder_PromotesAnonymousFields(t *testing.T) {
+	type Embedded struct {
+		A string `json:"a"`
+	}
+	type Outer struct {
+		Embedded
+		B string `json:"b"`
+	}
+	v := Outer{Embedded: Embedded{A: "hi"}, B: "there"}
+
+	want, err := Transform(v)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encoder.Encode: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Fatalf("Encoder output mismatch:\n got:  %s\n want: %s", buf.String(), want)
+	}
+	const wantJSON = `{"a":"hi","b":"there"}`
+	if buf.String() != wantJSON {
+		t.Fatalf("got %s, want %s", buf.String(), wantJSON)
+	}
+}
+
+// TestEncoder_AnonymousFieldWithJSONNameNotPromoted pins that an anonymous
+// field carrying an explicit json tag name is encoded as a regular nested
+// field instead of being promoted, matching encoding/json.
+func TestEncoder_AnonymousFieldWithJSONNameNotPromoted(t *testing.T) {
+	type Embedded struct {
+		A string `json:"a"`
+	}
+	type Outer struct {
+		Embedded `json:"embedded"`
+		B        string `json:"b"`
+	}
+	v := Outer{Embedded: Embedded{A: "hi"}, B: "there"}
+
+	want, err := Transform(v)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encoder.Encode: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Fatalf("Encoder output mismatch:\n got:  %s\n want: %s", buf.String(), want)
+	}
+}
+
+func TestEncoder_MaxDepthGuard(t *testing.T) {
+	// Build nested arrays nested deeper than MaxDepth.
+	var v interface{} = []interface{}{}
+	for i := 0; i < 5; i++ {
+		v = []interface{}{v}
+	}
+
+	enc := NewEncoder(&bytes.Buffer{})
+	enc.MaxDepth = 3
+	err := enc.Encode(v)
+	if err == nil {
+		t.Fatal("expected a MaxDepth error, got none")
+	}
+	if !strings.Contains(err.Error(), "MaxDepth") {
+		t.Fatalf("expected a MaxDepth error, got: %v", err)
+	}
+}
+
+func TestEncoder_DefaultMaxDepthAllowsModestNesting(t *testing.T) {
+	var v interface{} = 1
+	for i := 0; i < 50; i++ {
+		v = []interface{}{v}
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package canonicaljson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// encodeJCS writes v to w using RFC 8785 JSON Canonicalization Scheme
+// rules: object keys sorted by UTF-16 code unit, minimal escaping, and
+// ECMAScript-style number formatting.
+func encodeJCS(w io.Writer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		_, err := io.WriteString(w, "null")
+		return err
+	case bool:
+		if val {
+			_, err := io.WriteString(w, "true")
+			return err
+		}
+		_, err := io.WriteString(w, "false")
+		return err
+	case json.Number:
+		s, err := jcsNumber(val)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	case string:
+		return writeJCSString(w, val)
+	case []interface{}:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		for i, elem := range val {
+			if i > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			if err := encodeJCS(w, elem); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "]")
+		return err
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+		if _, err := io.WriteString(w, "{"); err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if i > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			if err := writeJCSString(w, k); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, ":"); err != nil {
+				return err
+			}
+			if err := encodeJCS(w, val[k]); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "}")
+		return err
+	default:
+		return fmt.Errorf("canonicaljson: unsupported type %T", v)
+	}
+}
+
+// lessUTF16 orders a and b by their UTF-16 code unit sequences, as required
+// by RFC 8785 §3.2.3 for object member ordering.
+func lessUTF16(a, b string) bool {
+	au := utf16.Encode([]rune(a))
+	bu := utf16.Encode([]rune(b))
+	for i := 0; i < len(au) && i < len(bu); i++ {
+		if au[i] != bu[i] {
+			return au[i] < bu[i]
+		}
+	}
+	return len(au) < len(bu)
+}
+
+// writeJCSString writes s to w as a JSON string using JCS's minimal
+// escaping rule (RFC 8785 §3.2.2.2): the quote, backslash, and the
+// mnemonic C0 controls get short escapes; any other character below
+// U+0020 gets \u00xx; everything else is emitted verbatim.
+func writeJCSString(w io.Writer, s string) error {
+	if _, err := io.WriteString(w, `"`); err != nil {
+		return err
+	}
+	for _, r := range s {
+		var err error
+		switch r {
+		case '"':
+			_, err = io.WriteString(w, `\"`)
+		case '\\':
+			_, err = io.WriteString(w, `\\`)
+		case '\b':
+			_, err = io.WriteString(w, `\b`)
+		case '\f':
+			_, err = io.WriteString(w, `\f`)
+		case '\n':
+			_, err = io.WriteString(w, `\n`)
+		case '\r':
+			_, err = io.WriteString(w, `\r`)
+		case '\t':
+			_, err = io.WriteString(w, `\t`)
+		default:
+			if r < 0x20 {
+				_, err = fmt.Fprintf(w, `\u%04x`, r)
+			} else {
+				_, err = io.WriteString(w, string(r))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
+
+// maxSafeInteger is 2^53, the I-JSON / ECMAScript safe-integer bound that
+// JCS integers must not exceed in magnitude.
+var maxSafeInteger = big.NewInt(1 << 53)
+
+// jcsNumber formats n per RFC 8785 §3.2.2.3: the ECMAScript
+// Number::toString algorithm applied to the IEEE 754 double value of n,
+// which is the shortest decimal string that round-trips to that double.
+//
+// Integer literals are checked against the I-JSON ±2^53 safe-integer bound
+// before that conversion happens: a json.Number's text is the exact value
+// the producer intended, so this catches values a silent float64 round-trip
+// would otherwise corrupt.
+func jcsNumber(n json.Number) (string, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return "", fmt.Errorf("canonicaljson: invalid number %q: %w", n, err)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("canonicaljson: NaN/Infinity are not valid JCS numbers")
+	}
+
+	if isIntegerLiteral(n) {
+		bi, ok := new(big.Int).SetString(n.String(), 10)
+		if !ok {
+			return "", fmt.Errorf("canonicaljson: invalid integer %q", n)
+		}
+		if bi.CmpAbs(maxSafeInteger) > 0 {
+			return "", fmt.Errorf("canonicaljson: integer %s exceeds the I-JSON safe range of ±2^53", n)
+		}
+		if f == 0 {
+			// Negative zero canonicalizes to "0".
+			return "0", nil
+		}
+		// Integral value within the safe range: print without a
+		// fractional part or exponent.
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+
+	if f == 0 {
+		// Negative zero canonicalizes to "0".
+		return "0", nil
+	}
+	return esNumberToString(f), nil
+}
+
+// isIntegerLiteral reports whether n's original text denotes an integer
+// (no fractional part or exponent), as opposed to merely having an integral
+// value after float64 conversion.
+func isIntegerLiteral(n json.Number) bool {
+	return !strings.ContainsAny(string(n), ".eE")
+}
+
+// esNumberToString implements the shortest round-trippable decimal
+// representation used by ECMAScript's Number::toString, which is what
+// RFC 8785 mandates for non-integer or out-of-safe-range numbers:
+// exponential notation is used when the decimal exponent k satisfies
+// k < -6 or k >= 21, and the exponent is written as "e+N"/"e-N" with no
+// leading zeros.
+func esNumberToString(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+
+	// strconv's 'g' format already chooses the shortest round-tripping
+	// digits; normalize its exponent spelling to match ECMAScript's
+	// "e+N" / "e-N" (no leading zero padding) and its switchover points.
+	mantissa, exp, hasExp := splitExponent(s)
+	if !hasExp {
+		return s
+	}
+	if exp >= -6 && exp < 21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	sign := "+"
+	if exp < 0 {
+		sign = "-"
+		exp = -exp
+	}
+	return fmt.Sprintf("%se%s%d", mantissa, sign, exp)
+}
+
+func splitExponent(s string) (mantissa string, exp int, ok bool) {
+	idx := -1
+	for i, c := range s {
+		if c == 'e' || c == 'E' {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return s, 0, false
+	}
+	mantissa = s[:idx]
+	e, err := strconv.Atoi(s[idx+1:])
+	if err != nil {
+		return s, 0, false
+	}
+	return mantissa, e, true
+}
+
@@ -0,0 +1,99 @@
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// encodeOLPC writes v to buf using the OLPC Canonical JSON dialect adopted
+// by TUF and in-toto: keys sorted as raw byte strings, no inter-token
+// whitespace, integers only (no floats), and minimal string escaping.
+func encodeOLPC(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if val {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		s, err := olpcNumber(val)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case string:
+		writeOLPCString(buf, val)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeOLPC(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		// OLPC orders keys as raw byte strings; Go's string comparison
+		// is already a byte-wise comparison of UTF-8 encoded strings.
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeOLPCString(buf, k)
+			buf.WriteByte(':')
+			if err := encodeOLPC(buf, val[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("canonicaljson: unsupported type %T", v)
+	}
+	return nil
+}
+
+// writeOLPCString escapes only the quote and backslash characters, per the
+// OLPC Canonical JSON string rule; every other byte, including controls and
+// non-ASCII, is emitted verbatim.
+func writeOLPCString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// olpcNumber formats n as a bare integer. OLPC Canonical JSON forbids
+// floating-point numbers outright, since their canonical representation is
+// not portable across languages - but unlike JCS, it has no int64 (or any
+// other) magnitude bound, so a bignum integer literal is accepted as-is.
+// isIntegerLiteral (shared with jcsNumber) distinguishes "has a fractional
+// part or exponent" from "too big for int64", which n.Int64() alone can't:
+// it fails for both reasons with the same error.
+func olpcNumber(n json.Number) (string, error) {
+	if !isIntegerLiteral(n) {
+		return "", fmt.Errorf("canonicaljson: OLPC scheme forbids non-integer number %q", n)
+	}
+	return n.String(), nil
+}
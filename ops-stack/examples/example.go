@@ -1,22 +1,21 @@
 package main
 
 // Example: Canonical JSON hashing in Go
-// This demonstrates using the webpki/jcs library for RFC 8785 canonicalization
+// This demonstrates ops-stack/canonicaljson, which canonicalizes a value
+// under RFC 8785 JCS (the default scheme) before hashing it, so the hash
+// is stable across equivalent encodings of the same data.
 //
 // To run:
-//   go install github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer@latest
 //   go run ops-stack/examples/example.go
 
 import (
-	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
-)
 
-// Note: In a real implementation, you would import:
-// import "webpki.org/jsoncanonicalizer"
+	"github.com/EvezArt/openclaw/ops-stack/canonicaljson"
+)
 
 type TestData struct {
 	TestID    string                 `json:"testId"`
@@ -46,7 +45,9 @@ func main() {
 		},
 	}
 
-	// Standard JSON marshaling (not canonical)
+	// Standard JSON marshaling (not canonical: struct fields come out in
+	// declaration order rather than sorted, and string escaping differs
+	// from RFC 8785).
 	standardJSON, err := json.Marshal(data)
 	if err != nil {
 		log.Fatal(err)
@@ -55,21 +56,18 @@ func main() {
 	fmt.Println(string(standardJSON))
 	fmt.Println()
 
-	// Canonical JSON (would use jsoncanonicalizer.Transform)
-	// canonical, err := jsoncanonicalizer.Transform(data)
-	// if err != nil {
-	//     log.Fatal(err)
-	// }
-	// fmt.Println("Canonical JSON:")
-	// fmt.Println(string(canonical))
-
-	// For this example, we'll use standard JSON
-	// In production, use the canonicalized form
-	hash := sha256.Sum256(standardJSON)
-	fmt.Println("SHA-256 Hash:")
-	fmt.Println(hex.EncodeToString(hash[:]))
+	canonical, err := canonicaljson.Transform(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Canonical JSON (JCS):")
+	fmt.Println(string(canonical))
 	fmt.Println()
 
-	fmt.Println("To enable full canonical hashing:")
-	fmt.Println("  go install github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer@latest")
+	hash, err := canonicaljson.HashSHA256(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("SHA-256 Hash (of canonical JSON):")
+	fmt.Println(hex.EncodeToString(hash[:]))
 }
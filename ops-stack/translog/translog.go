@@ -0,0 +1,320 @@
+// Package translog implements an append-only transparency log over
+// canonical-JSON records, backed by an RFC 6962-style Merkle tree.
+//
+// Every record is hashed into a leaf with canonicaljson.Transform so that
+// two equivalent encodings of the same record produce the same leaf, and
+// leaves are combined into a tree whose root changes whenever any record
+// changes or any record is appended. A client holding only a leaf hash and
+// an inclusion proof can confirm the record is present in the log without
+// trusting whoever serves the log; two roots plus a consistency proof let
+// a client confirm the log only ever grew, and never rewrote history.
+package translog
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/EvezArt/openclaw/ops-stack/canonicaljson"
+)
+
+// Storage persists the leaf hashes and records of a Log. Implementations
+// must preserve append order: index 0 is the first record ever appended.
+type Storage interface {
+	// AppendLeaf stores a new leaf and returns its index.
+	AppendLeaf(hash, record []byte) (index uint64, err error)
+	// Leaf returns the hash and record previously stored at index.
+	Leaf(index uint64) (hash, record []byte, err error)
+	// Size returns the number of leaves stored so far.
+	Size() (uint64, error)
+}
+
+// Log is an append-only, tamper-evident log of canonical-JSON records.
+type Log struct {
+	mu      sync.Mutex
+	storage Storage
+}
+
+// New returns a Log backed by storage.
+func New(storage Storage) *Log {
+	return &Log{storage: storage}
+}
+
+// Append canonicalizes record, stores it, and returns its index and leaf
+// hash.
+func (l *Log) Append(record interface{}) (index uint64, leaf []byte, err error) {
+	body, err := canonicaljson.Transform(record)
+	if err != nil {
+		return 0, nil, fmt.Errorf("translog: canonicalize record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lh := leafHash(body)
+	index, err = l.storage.AppendLeaf(lh, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("translog: append: %w", err)
+	}
+	return index, lh, nil
+}
+
+// Root returns the current Merkle tree head: the root hash over every leaf
+// appended so far.
+func (l *Log) Root() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	leaves, err := l.leafHashes(0)
+	if err != nil {
+		return nil, err
+	}
+	return rootHash(leaves), nil
+}
+
+// InclusionProof returns the audit path proving that the leaf at index is
+// included in the tree over all leaves appended so far.
+func (l *Log) InclusionProof(index uint64) ([][]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	leaves, err := l.leafHashes(0)
+	if err != nil {
+		return nil, err
+	}
+	if index >= uint64(len(leaves)) {
+		return nil, fmt.Errorf("translog: index %d out of range (size %d)", index, len(leaves))
+	}
+	return auditPath(leaves, int(index)), nil
+}
+
+// ConsistencyProof returns the proof that the tree of size newSize is an
+// extension of the tree of size oldSize: every leaf present at oldSize is
+// still present, in the same order, at newSize.
+func (l *Log) ConsistencyProof(oldSize, newSize uint64) ([][]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	size, err := l.storage.Size()
+	if err != nil {
+		return nil, fmt.Errorf("translog: size: %w", err)
+	}
+	if oldSize > newSize || newSize > size {
+		return nil, fmt.Errorf("translog: invalid range [%d, %d] for log of size %d", oldSize, newSize, size)
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return nil, nil
+	}
+
+	leaves, err := l.leafHashes(newSize)
+	if err != nil {
+		return nil, err
+	}
+	return consistencyProof(leaves, oldSize), nil
+}
+
+// leafHashes returns the hashes of every leaf up to n (or to the log's
+// current size if n is 0).
+func (l *Log) leafHashes(n uint64) ([][]byte, error) {
+	size, err := l.storage.Size()
+	if err != nil {
+		return nil, fmt.Errorf("translog: size: %w", err)
+	}
+	if n == 0 || n > size {
+		n = size
+	}
+
+	leaves := make([][]byte, n)
+	for i := uint64(0); i < n; i++ {
+		hash, _, err := l.storage.Leaf(i)
+		if err != nil {
+			return nil, fmt.Errorf("translog: leaf %d: %w", i, err)
+		}
+		leaves[i] = hash
+	}
+	return leaves, nil
+}
+
+// VerifyInclusion reconstructs a root from leaf, its index, the tree size,
+// and an audit path, and reports whether it matches root.
+func VerifyInclusion(leaf []byte, index, size uint64, proof [][]byte, root []byte) bool {
+	got, ok := rootFromAuditPath(leaf, index, size, proof)
+	return ok && string(got) == string(root)
+}
+
+// VerifyConsistency reports whether proof demonstrates that the tree of
+// size newSize with root newRoot is an append-only extension of the tree of
+// size oldSize with root oldRoot: every leaf present at oldSize is still
+// present, in the same order, at newSize. This is the verifier-side
+// counterpart to ConsistencyProof, following RFC 6962 §2.1.2's verification
+// algorithm.
+func VerifyConsistency(oldRoot, newRoot []byte, oldSize, newSize uint64, proof [][]byte) bool {
+	if oldSize > newSize {
+		return false
+	}
+	if oldSize == 0 {
+		return len(proof) == 0
+	}
+	if oldSize == newSize {
+		return len(proof) == 0 && string(oldRoot) == string(newRoot)
+	}
+
+	node := oldSize - 1
+	lastNode := newSize - 1
+	for node%2 == 1 {
+		node /= 2
+		lastNode /= 2
+	}
+
+	var fn, sn []byte
+	if node > 0 {
+		if len(proof) == 0 {
+			return false
+		}
+		fn, sn = proof[0], proof[0]
+		proof = proof[1:]
+	} else {
+		fn, sn = oldRoot, oldRoot
+	}
+
+	for _, c := range proof {
+		if lastNode == 0 {
+			return false
+		}
+		if node%2 == 1 || node == lastNode {
+			fn = nodeHash(c, fn)
+			sn = nodeHash(c, sn)
+			for node%2 == 0 && node > 0 {
+				node /= 2
+				lastNode /= 2
+			}
+		} else {
+			sn = nodeHash(sn, c)
+		}
+		node /= 2
+		lastNode /= 2
+	}
+
+	return string(fn) == string(oldRoot) && string(sn) == string(newRoot)
+}
+
+// leafHash is RFC 6962's leaf hash: SHA256(0x00 || data).
+func leafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// nodeHash is RFC 6962's internal node hash: SHA256(0x01 || left || right).
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// emptyHash is the root of a tree with no leaves: SHA256("").
+func emptyHash() []byte {
+	h := sha256.Sum256(nil)
+	return h[:]
+}
+
+// rootHash computes RFC 6962's Merkle Tree Hash (MTH) over leaves.
+func rootHash(leaves [][]byte) []byte {
+	n := len(leaves)
+	switch {
+	case n == 0:
+		return emptyHash()
+	case n == 1:
+		return leaves[0]
+	default:
+		k := largestPowerOfTwoLessThan(n)
+		return nodeHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+	}
+}
+
+// auditPath computes RFC 6962's PATH(m, D[n]): the sequence of hashes a
+// verifier combines with D[m] to recompute MTH(D[n]).
+func auditPath(leaves [][]byte, m int) [][]byte {
+	return path(m, leaves)
+}
+
+func path(m int, d [][]byte) [][]byte {
+	n := len(d)
+	if n <= 1 {
+		return nil
+	}
+	k := largestPowerOfTwoLessThan(n)
+	if m < k {
+		return append(path(m, d[:k]), rootHash(d[k:]))
+	}
+	return append(path(m-k, d[k:]), rootHash(d[:k]))
+}
+
+// consistencyProof computes RFC 6962's PROOF(m, D[n]) = SUBPROOF(m, D[n], true).
+func consistencyProof(d [][]byte, m uint64) [][]byte {
+	return subProof(int(m), d, true)
+}
+
+func subProof(m int, d [][]byte, b bool) [][]byte {
+	n := len(d)
+	if m == n {
+		if b {
+			return nil
+		}
+		return [][]byte{rootHash(d)}
+	}
+	k := largestPowerOfTwoLessThan(n)
+	if m <= k {
+		return append(subProof(m, d[:k], b), rootHash(d[k:]))
+	}
+	return append(subProof(m-k, d[k:], false), rootHash(d[:k]))
+}
+
+// rootFromAuditPath recomputes a root hash from a leaf, its index, the
+// total tree size, and an audit path, following RFC 6962 §2.1.1's
+// verification algorithm. The proof is consumed in the same order
+// auditPath/path produced it: innermost (closest to the leaf) first.
+func rootFromAuditPath(leaf []byte, index, size uint64, proof [][]byte) ([]byte, bool) {
+	pos := 0
+	node, ok := verifyPath(int(index), int(size), proof, &pos, leaf)
+	if !ok || pos != len(proof) {
+		return nil, false
+	}
+	return node, true
+}
+
+func verifyPath(m, n int, proof [][]byte, pos *int, node []byte) ([]byte, bool) {
+	if n <= 1 {
+		return node, true
+	}
+	k := largestPowerOfTwoLessThan(n)
+	if m < k {
+		child, ok := verifyPath(m, k, proof, pos, node)
+		if !ok || *pos >= len(proof) {
+			return nil, false
+		}
+		sibling := proof[*pos]
+		*pos++
+		return nodeHash(child, sibling), true
+	}
+	child, ok := verifyPath(m-k, n-k, proof, pos, node)
+	if !ok || *pos >= len(proof) {
+		return nil, false
+	}
+	sibling := proof[*pos]
+	*pos++
+	return nodeHash(sibling, child), true
+}
+
+// largestPowerOfTwoLessThan returns the largest power of two strictly less
+// than n, for n >= 2.
+func largestPowerOfTwoLessThan(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
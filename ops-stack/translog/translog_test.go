@@ -0,0 +1,180 @@
+package translog
+
+import "testing"
+
+func TestLog_EmptyRoot(t *testing.T) {
+	l := New(NewMemoryStorage())
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	if string(root) != string(emptyHash()) {
+		t.Fatalf("Root of empty log = %x, want %x", root, emptyHash())
+	}
+}
+
+// appendN appends n distinct records to l and returns their leaf hashes in
+// order.
+func appendN(t *testing.T, l *Log, n int) [][]byte {
+	t.Helper()
+	leaves := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		_, leaf, err := l.Append(map[string]interface{}{"i": i})
+		if err != nil {
+			t.Fatalf("Append(%d): %v", i, err)
+		}
+		leaves[i] = leaf
+	}
+	return leaves
+}
+
+// TestInclusionRoundTrip exercises Append/Root/InclusionProof/VerifyInclusion
+// across tree sizes that matter for RFC 6962's recursive splitting: 1, 2,
+// powers of two, and sizes either side of one.
+func TestInclusionRoundTrip(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 4, 5, 7, 8, 16, 17} {
+		size := size
+		t.Run("", func(t *testing.T) {
+			l := New(NewMemoryStorage())
+			leaves := appendN(t, l, size)
+
+			root, err := l.Root()
+			if err != nil {
+				t.Fatalf("Root: %v", err)
+			}
+
+			for i := range leaves {
+				proof, err := l.InclusionProof(uint64(i))
+				if err != nil {
+					t.Fatalf("InclusionProof(%d): %v", i, err)
+				}
+				if !VerifyInclusion(leaves[i], uint64(i), uint64(size), proof, root) {
+					t.Errorf("VerifyInclusion(%d) of %d failed", i, size)
+				}
+			}
+		})
+	}
+}
+
+func TestInclusionProof_IndexOutOfRange(t *testing.T) {
+	l := New(NewMemoryStorage())
+	appendN(t, l, 3)
+	if _, err := l.InclusionProof(3); err == nil {
+		t.Fatal("expected an error for an out-of-range index, got none")
+	}
+}
+
+// TestVerifyInclusion_RejectsTamperedLeaf pins that flipping a leaf hash
+// (simulating a server that serves a different record than the one it
+// proves membership for) makes the proof fail to verify.
+func TestVerifyInclusion_RejectsTamperedLeaf(t *testing.T) {
+	l := New(NewMemoryStorage())
+	leaves := appendN(t, l, 5)
+
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	proof, err := l.InclusionProof(2)
+	if err != nil {
+		t.Fatalf("InclusionProof: %v", err)
+	}
+
+	tampered := append([]byte(nil), leaves[2]...)
+	tampered[0] ^= 0xff
+	if VerifyInclusion(tampered, 2, 5, proof, root) {
+		t.Fatal("expected VerifyInclusion to reject a tampered leaf hash")
+	}
+}
+
+func TestVerifyInclusion_RejectsTamperedProof(t *testing.T) {
+	l := New(NewMemoryStorage())
+	leaves := appendN(t, l, 5)
+
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	proof, err := l.InclusionProof(2)
+	if err != nil {
+		t.Fatalf("InclusionProof: %v", err)
+	}
+	if len(proof) == 0 {
+		t.Fatal("expected a non-empty proof for a 5-leaf tree")
+	}
+
+	tampered := make([][]byte, len(proof))
+	copy(tampered, proof)
+	bad := append([]byte(nil), tampered[0]...)
+	bad[0] ^= 0xff
+	tampered[0] = bad
+
+	if VerifyInclusion(leaves[2], 2, 5, tampered, root) {
+		t.Fatal("expected VerifyInclusion to reject a tampered audit path")
+	}
+}
+
+// TestConsistencyRoundTrip exercises ConsistencyProof/VerifyConsistency
+// across a range of (oldSize, newSize) pairs, including oldSize == 0 and
+// oldSize == newSize.
+func TestConsistencyRoundTrip(t *testing.T) {
+	l := New(NewMemoryStorage())
+	const maxSize = 17
+
+	roots := make([][]byte, maxSize+1)
+	root, err := l.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+	roots[0] = root
+
+	for i := 1; i <= maxSize; i++ {
+		if _, _, err := l.Append(map[string]interface{}{"i": i}); err != nil {
+			t.Fatalf("Append(%d): %v", i, err)
+		}
+		root, err := l.Root()
+		if err != nil {
+			t.Fatalf("Root at size %d: %v", i, err)
+		}
+		roots[i] = root
+	}
+
+	for oldSize := 0; oldSize <= maxSize; oldSize++ {
+		for newSize := oldSize; newSize <= maxSize; newSize++ {
+			proof, err := l.ConsistencyProof(uint64(oldSize), uint64(newSize))
+			if err != nil {
+				t.Fatalf("ConsistencyProof(%d, %d): %v", oldSize, newSize, err)
+			}
+			if !VerifyConsistency(roots[oldSize], roots[newSize], uint64(oldSize), uint64(newSize), proof) {
+				t.Errorf("VerifyConsistency(%d, %d) failed", oldSize, newSize)
+			}
+		}
+	}
+}
+
+func TestVerifyConsistency_RejectsForgedOldRoot(t *testing.T) {
+	l := New(NewMemoryStorage())
+	appendN(t, l, 7)
+
+	proof, err := l.ConsistencyProof(3, 7)
+	if err != nil {
+		t.Fatalf("ConsistencyProof: %v", err)
+	}
+	newRoot, err := l.Root()
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+
+	forgedOldRoot := append([]byte(nil), newRoot...)
+	forgedOldRoot[0] ^= 0xff
+
+	if VerifyConsistency(forgedOldRoot, newRoot, 3, 7, proof) {
+		t.Fatal("expected VerifyConsistency to reject a forged old root")
+	}
+}
+
+func TestVerifyConsistency_InvalidRange(t *testing.T) {
+	if VerifyConsistency(nil, nil, 5, 3, nil) {
+		t.Fatal("expected VerifyConsistency to reject oldSize > newSize")
+	}
+}